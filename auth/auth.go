@@ -0,0 +1,237 @@
+// Package auth provides an optional bcrypt-based login/logout helper layered
+// on top of simplesessions.SessionManager. It lives in its own subpackage so
+// that callers who only want raw sessions aren't forced to depend on
+// golang.org/x/crypto/bcrypt - Go compiles whole packages together, so a
+// dependency in the main package would be pulled in by every importer
+// regardless of whether they ever touch Auth.
+package auth
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	simplesessions "github.com/markorm/simplesessions"
+)
+
+// === User ===
+// A bcrypt-hashed credential pair Auth checks Login attempts against
+// Uid is stored on the Session created by a successful Login
+type User struct {
+	Uid          int
+	Name         string
+	PasswordHash []byte
+}
+
+// === User Store ===
+// Looks up a User by name; callers back this with whatever table/file they
+// already keep user records in
+type UserStore interface {
+	UserByName(name string) (*User, error)
+}
+
+// === Rate Limiter ===
+// Tracks failed login attempts per key (normally a client IP) so Login can
+// block further attempts once too many have failed within a window
+// Swap in your own implementation (e.g. Redis backed, shared across
+// processes) via NewAuthWithLimiter
+type RateLimiter interface {
+	Allow(key string) bool
+	Fail(key string)
+	Reset(key string)
+}
+
+// === Auth Options ===
+// Configures the default in-memory RateLimiter built by NewAuth, and which
+// proxies are trusted to set X-Forwarded-For
+// @param MaxAttempts:		failed attempts allowed within Window before a key is blocked, defaults to 5
+// @param Window:			the rolling window MaxAttempts is counted over, defaults to 1 minute
+// @param TrustedProxies:	CIDRs of proxies allowed to set X-Forwarded-For; the header is ignored unless r.RemoteAddr falls inside one of these
+type AuthOptions struct {
+	MaxAttempts		int
+	Window			time.Duration
+	TrustedProxies	[]string
+}
+
+// === Auth ===
+// Optional bcrypt-based login/logout layered on top of a SessionManager
+type Auth struct {
+	sm             *simplesessions.SessionManager
+	users          UserStore
+	limiter        RateLimiter
+	trustedProxies []*net.IPNet
+}
+
+// === New Auth ===
+// Constructor for Auth using the default in-memory RateLimiter
+// @param sm:	the session manager Login/Logout sessions are created on
+// @param users:	backing store Login looks up credentials in
+// @param opts:	rate limiter and trusted proxy configuration
+func NewAuth(sm *simplesessions.SessionManager, users UserStore, opts AuthOptions) (*Auth, error) {
+	return NewAuthWithLimiter(sm, users, newAuthRateLimiter(opts), opts.TrustedProxies)
+}
+
+// === New Auth With Limiter ===
+// Constructor for Auth with a pluggable RateLimiter
+// @param sm:				the session manager Login/Logout sessions are created on
+// @param users:			backing store Login looks up credentials in
+// @param limiter:			rate limiter Login checks before and updates after each attempt
+// @param trustedProxies:	CIDRs of proxies allowed to set X-Forwarded-For
+func NewAuthWithLimiter(sm *simplesessions.SessionManager, users UserStore, limiter RateLimiter, trustedProxies []string) (*Auth, error) {
+	proxies, err := parseTrustedProxies(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return &Auth{sm: sm, users: users, limiter: limiter, trustedProxies: proxies}, nil
+}
+
+// parseTrustedProxies turns a list of CIDRs into the *net.IPNet values
+// clientIP checks r.RemoteAddr against
+func parseTrustedProxies(cidrs []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// === Login ===
+// Verify name/password against the UserStore and start a session for the
+// matching user, writing its cookie to w
+// Blocks the request's client IP after too many failed attempts within the
+// configured window
+// @param w:		the writer interface
+// @param r:		the request, used to read the client's IP
+// @param name:		the username to authenticate
+// @param password:	the plaintext password to check against the stored bcrypt hash
+func (a *Auth) Login(w http.ResponseWriter, r *http.Request, name, password string) (*simplesessions.Session, error) {
+	ip := a.clientIP(r)
+	if !a.limiter.Allow(ip) {
+		return nil, errors.New("Too many failed login attempts, try again later")
+	}
+	u, err := a.users.UserByName(name)
+	if err != nil {
+		a.limiter.Fail(ip)
+		return nil, errors.New("Invalid credentials")
+	}
+	if err := bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(password)); err != nil {
+		a.limiter.Fail(ip)
+		return nil, errors.New("Invalid credentials")
+	}
+	a.limiter.Reset(ip)
+	s, err := a.sm.CreateSession(u.Uid)
+	if err != nil {
+		return nil, err
+	}
+	a.sm.SetCookie(w, s)
+	return s, nil
+}
+
+// === Logout ===
+// Destroy the session tied to r's cookie and clear it from the browser
+// @param w:	the writer interface
+// @param r:	the request to read the existing session cookie from
+func (a *Auth) Logout(w http.ResponseWriter, r *http.Request) error {
+	return a.sm.Destroy(w, r)
+}
+
+// clientIP returns the address the rate limiter should key on
+// X-Forwarded-For is only honored when r.RemoteAddr falls inside one of
+// a.trustedProxies - otherwise any client could set the header themselves
+// and get a fresh rate-limit bucket on every attempt
+func (a *Auth) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !a.isTrustedProxy(host) {
+		return host
+	}
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		return strings.TrimSpace(strings.Split(fwd, ",")[0])
+	}
+	return host
+}
+
+func (a *Auth) isTrustedProxy(host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, n := range a.trustedProxies {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// === Auth Rate Limiter ===
+// Default in-memory RateLimiter, built by NewAuth from AuthOptions
+type authRateLimiter struct {
+	mu          sync.Mutex
+	maxAttempts int
+	window      time.Duration
+	failures    map[string][]time.Time
+}
+
+func newAuthRateLimiter(opts AuthOptions) *authRateLimiter {
+	max := opts.MaxAttempts
+	if max <= 0 {
+		max = 5
+	}
+	window := opts.Window
+	if window <= 0 {
+		window = time.Minute
+	}
+	return &authRateLimiter{
+		maxAttempts: max,
+		window:      window,
+		failures:    make(map[string][]time.Time),
+	}
+}
+
+func (l *authRateLimiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(key)
+	return len(l.failures[key]) < l.maxAttempts
+}
+
+func (l *authRateLimiter) Fail(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(key)
+	l.failures[key] = append(l.failures[key], time.Now())
+}
+
+func (l *authRateLimiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.failures, key)
+}
+
+// prune drops failures outside the window; caller must hold l.mu
+func (l *authRateLimiter) prune(key string) {
+	cutoff := time.Now().Add(-l.window)
+	kept := l.failures[key][:0]
+	for _, t := range l.failures[key] {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	if len(kept) == 0 {
+		delete(l.failures, key)
+		return
+	}
+	l.failures[key] = kept
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuthRateLimiterWindow(t *testing.T) {
+	l := newAuthRateLimiter(AuthOptions{MaxAttempts: 3, Window: 50 * time.Millisecond})
+
+	for i := 0; i < 3; i++ {
+		if !l.Allow("1.2.3.4") {
+			t.Fatalf("attempt %d: expected Allow to still be true", i)
+		}
+		l.Fail("1.2.3.4")
+	}
+	if l.Allow("1.2.3.4") {
+		t.Fatal("expected Allow to be false after MaxAttempts failures")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if !l.Allow("1.2.3.4") {
+		t.Fatal("expected Allow to be true again once failures age out of the window")
+	}
+
+	// A different key must never be affected by another key's failures
+	if !l.Allow("5.6.7.8") {
+		t.Fatal("expected an unrelated key to be unaffected")
+	}
+}
+
+func TestAuthRateLimiterReset(t *testing.T) {
+	l := newAuthRateLimiter(AuthOptions{MaxAttempts: 1, Window: time.Minute})
+	l.Fail("1.2.3.4")
+	if l.Allow("1.2.3.4") {
+		t.Fatal("expected Allow to be false after the single allowed failure")
+	}
+	l.Reset("1.2.3.4")
+	if !l.Allow("1.2.3.4") {
+		t.Fatal("expected Allow to be true after Reset")
+	}
+}
+
+// X-Forwarded-For must only be honored when the immediate peer is a
+// configured trusted proxy - otherwise any client can set it themselves and
+// get a fresh rate-limit bucket on every attempt.
+func TestClientIPOnlyTrustsConfiguredProxies(t *testing.T) {
+	a := &Auth{}
+	var err error
+	a.trustedProxies, err = parseTrustedProxies([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("parseTrustedProxies: %v", err)
+	}
+
+	untrusted := httptest.NewRequest("POST", "/login", nil)
+	untrusted.RemoteAddr = "203.0.113.9:1234"
+	untrusted.Header.Set("X-Forwarded-For", "1.1.1.1")
+	if got := a.clientIP(untrusted); got != "203.0.113.9" {
+		t.Fatalf("clientIP from untrusted peer = %q, want RemoteAddr host", got)
+	}
+
+	trusted := httptest.NewRequest("POST", "/login", nil)
+	trusted.RemoteAddr = "10.0.0.5:1234"
+	trusted.Header.Set("X-Forwarded-For", "1.1.1.1, 10.0.0.5")
+	if got := a.clientIP(trusted); got != "1.1.1.1" {
+		t.Fatalf("clientIP from trusted peer = %q, want forwarded address", got)
+	}
+}
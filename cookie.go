@@ -0,0 +1,73 @@
+package simpleSessions
+
+import (
+	"net/http"
+	"time"
+)
+
+// newCookie builds an *http.Cookie with every hardening attribute from
+// SessionOptions applied, so SetCookie/Destroy/NewSession never have to
+// repeat them
+func (sm *SessionManager) newCookie(value string, expires time.Time) *http.Cookie {
+	path := sm.Options.CookiePath
+	if path == "" {
+		path = "/"
+	}
+	return &http.Cookie{
+		Name:     sm.Options.CookieName,
+		Value:    value,
+		Path:     path,
+		Domain:   sm.Options.Domain,
+		Expires:  expires,
+		MaxAge:   sm.Options.MaxAge,
+		Secure:   sm.Options.Secure,
+		HttpOnly: sm.Options.HttpOnly,
+		SameSite: sm.Options.SameSite,
+	}
+}
+
+// cookieValue returns the value written for a session cookie, signing it
+// when SessionOptions.SignCookies is set
+func (sm *SessionManager) cookieValue(s *Session) string {
+	if sm.Options.SignCookies {
+		return s.Id + "|" + SignSID(sm.Options.Salt, s.Id)
+	}
+	return s.Id
+}
+
+// === Set Cookie ===
+// Push a session cookie to the response writer
+// @param w: 	the writer interface
+// @param s: 	the session to set a cookie for
+func (sm *SessionManager) SetCookie(w http.ResponseWriter, s *Session) {
+	c := s.Cookie
+	c.Name = sm.Options.CookieName
+	c.Value = sm.cookieValue(s)
+	c.Expires = s.Expires
+	http.SetCookie(w, c)
+}
+
+// === Destroy ===
+// Remove a session server-side and write a matching expired Set-Cookie so
+// the browser clears it
+// Matching Name/Path/Domain/Secure/SameSite is required, or some browsers
+// (Chrome among them) will refuse to clear the cookie
+// @param w:	the writer interface
+// @param r:	the request to read the existing session cookie from
+func (sm *SessionManager) Destroy(w http.ResponseWriter, r *http.Request) error {
+	c, err := r.Cookie(sm.Options.CookieName)
+	if err != nil {
+		return err
+	}
+	id := c.Value
+	if sm.Options.SignCookies {
+		if verified, ok := sm.verifySignedSID(id); ok {
+			id = verified
+		}
+	}
+	sm.store.Destroy(id)
+	expired := sm.newCookie("", time.Unix(0, 0))
+	expired.MaxAge = -1
+	http.SetCookie(w, expired)
+	return nil
+}
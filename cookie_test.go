@@ -0,0 +1,66 @@
+package simpleSessions
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Destroy's expired Set-Cookie must match the Name/Path/Domain/Secure
+// attributes of the cookie it's clearing, or some browsers (Chrome among
+// them) silently refuse to delete it.
+func TestDestroyWritesMatchingExpiredCookie(t *testing.T) {
+	sm, err := NewSessionManager(SessionOptions{
+		CookieName: "sid",
+		Salt:       "test-salt",
+		Timeout:    5,
+		Provider:   "memory",
+		CookiePath: "/app",
+		Domain:     "example.com",
+		Secure:     true,
+		HttpOnly:   true,
+	})
+	if err != nil {
+		t.Fatalf("NewSessionManager: %v", err)
+	}
+	defer sm.Close()
+
+	id, err := sm.NewSession(1)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: sm.Options.CookieName, Value: sm.cookieValue(&Session{Id: id})})
+
+	w := httptest.NewRecorder()
+	if err := sm.Destroy(w, req); err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+
+	resp := w.Result()
+	cookies := resp.Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected exactly one Set-Cookie, got %d", len(cookies))
+	}
+	c := cookies[0]
+	if c.Name != "sid" {
+		t.Errorf("Name = %q, want %q", c.Name, "sid")
+	}
+	if c.Path != "/app" {
+		t.Errorf("Path = %q, want %q", c.Path, "/app")
+	}
+	if c.Domain != "example.com" {
+		t.Errorf("Domain = %q, want %q", c.Domain, "example.com")
+	}
+	if !c.Secure {
+		t.Error("Secure = false, want true")
+	}
+	if c.MaxAge >= 0 {
+		t.Errorf("MaxAge = %d, want negative", c.MaxAge)
+	}
+
+	if _, err := sm.GetSession(id); err == nil {
+		t.Fatal("session should have been destroyed server-side")
+	}
+}
@@ -0,0 +1,50 @@
+package simpleSessions
+
+// === Set ===
+// Store an arbitrary key/value pair on the session and persist it through
+// whichever provider the session was loaded from
+// @param key:		the key to store the value under
+// @param value:	the value to store
+func (s *Session) Set(key, value interface{}) error {
+	if s.Data == nil {
+		s.Data = make(map[interface{}]interface{})
+	}
+	s.Data[key] = value
+	return s.persist()
+}
+
+// === Get ===
+// Return the value stored under key, or nil if it isn't set
+// @param key:	the key to look up
+func (s *Session) Get(key interface{}) interface{} {
+	if s.Data == nil {
+		return nil
+	}
+	return s.Data[key]
+}
+
+// === Delete ===
+// Remove a single key from the session and persist it
+// @param key:	the key to remove
+func (s *Session) Delete(key interface{}) error {
+	if s.Data != nil {
+		delete(s.Data, key)
+	}
+	return s.persist()
+}
+
+// === Flush ===
+// Clear all data held on the session and persist it
+func (s *Session) Flush() error {
+	s.Data = make(map[interface{}]interface{})
+	return s.persist()
+}
+
+// persist writes the session back through the manager it was loaded from
+// A no-op for sessions that haven't been attached to a manager yet
+func (s *Session) persist() error {
+	if s.sm == nil {
+		return nil
+	}
+	return s.sm.store.Write(s)
+}
@@ -0,0 +1,73 @@
+package simpleSessions
+
+import (
+	"testing"
+	"time"
+)
+
+// GetSession used to call ClearExpired() from inside a loop ranging over the
+// very session slice it mutated - dropping some expired sessions silently
+// depending on iteration order. The provider redesign replaced that slice,
+// but GC must still reliably clear every expired session in one pass, not
+// just some of them.
+func TestGCClearsAllExpiredSessions(t *testing.T) {
+	sm, err := NewSessionManager(SessionOptions{
+		CookieName: "sid",
+		Salt:       "test-salt",
+		Timeout:    -1, // sessions expire the instant they're created
+		Provider:   "memory",
+	})
+	if err != nil {
+		t.Fatalf("NewSessionManager: %v", err)
+	}
+	defer sm.Close()
+
+	var ids []string
+	for i := 0; i < 10; i++ {
+		id, err := sm.NewSession(i)
+		if err != nil {
+			t.Fatalf("NewSession: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if got := sm.GC(); got != len(ids) {
+		t.Fatalf("GC() = %d, want %d", got, len(ids))
+	}
+
+	for _, id := range ids {
+		if _, err := sm.GetSession(id); err == nil {
+			t.Fatalf("session %q should have been cleared by GC", id)
+		}
+	}
+}
+
+// NewSessionManager must start the background ticker when GCLifetime is set
+// so expired sessions get swept without anyone calling GC or GetSession.
+func TestGCRunsOnTicker(t *testing.T) {
+	sm, err := NewSessionManager(SessionOptions{
+		CookieName: "sid",
+		Salt:       "test-salt",
+		Timeout:    -1,
+		Provider:   "memory",
+		GCLifetime: 10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewSessionManager: %v", err)
+	}
+	defer sm.Close()
+
+	id, err := sm.NewSession(1)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := sm.store.Read(id); err != nil {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("expected background GC to clear the expired session")
+}
@@ -0,0 +1,76 @@
+package simpleSessions
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// contextKey is an unexported type so Session values stashed in a request
+// context can't collide with keys set by other packages
+type contextKey int
+
+const sessionContextKey contextKey = 0
+
+// === Start ===
+// Read the configured cookie from r and look up the matching session,
+// creating a guest session (Uid -1) if one is absent or expired
+// Refreshes the session's expiry, writes the cookie back to w, and returns
+// the live session
+// @param w:	the writer interface
+// @param r:	the request to read the existing session cookie from
+func (sm *SessionManager) Start(w http.ResponseWriter, r *http.Request) (*Session, error) {
+	s := sm.loadFromRequest(r)
+	if s == nil {
+		created, err := sm.CreateSession(-1)
+		if err != nil {
+			return nil, err
+		}
+		s = created
+	}
+	s.Expires = time.Now().Add(sm.Options.Timeout * time.Minute)
+	s.Cookie = sm.newCookie(sm.cookieValue(s), s.Expires)
+	if err := sm.store.Write(s); err != nil {
+		return nil, err
+	}
+	sm.SetCookie(w, s)
+	return s, nil
+}
+
+// loadFromRequest returns the live session for r's session cookie, or nil
+// if there isn't one or it's no longer valid
+func (sm *SessionManager) loadFromRequest(r *http.Request) *Session {
+	c, err := r.Cookie(sm.Options.CookieName)
+	if err != nil {
+		return nil
+	}
+	s, err := sm.GetSession(c.Value)
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
+// === Middleware ===
+// Wrap h so Start has already run on every request; the live session can be
+// read back out with SessionFromContext
+// @param h:	the handler to wrap
+func (sm *SessionManager) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := sm.Start(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		ctx := context.WithValue(r.Context(), sessionContextKey, s)
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// === Session From Context ===
+// Return the *Session stashed by Middleware, or nil if none is present
+// @param ctx:	a request context that has passed through Middleware
+func SessionFromContext(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionContextKey).(*Session)
+	return s
+}
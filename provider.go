@@ -0,0 +1,51 @@
+package simpleSessions
+
+import "fmt"
+
+// === Provider ===
+// Storage backend for sessions. Built-in implementations are provided for
+// memory, file and Redis backed storage; third parties can implement this
+// interface to plug in their own backend (MySQL, Memcache, etc).
+type Provider interface {
+	// Read returns the session matching id, or an error if none exists
+	Read(id string) (*Session, error)
+	// Write persists a session, overwriting any existing session with the same id
+	Write(s *Session) error
+	// Destroy removes a session matching id
+	Destroy(id string) error
+	// GC removes all expired sessions and returns the number removed
+	GC() int
+	// All returns every session currently held by the provider
+	All() ([]*Session, error)
+}
+
+// === Provider Registry ===
+// Built-in and third party providers register themselves here by name so a
+// SessionManager can be pointed at one via SessionOptions.Provider
+var providers = make(map[string]Provider)
+
+// === Register ===
+// Make a provider available under name for use by SessionManager
+// Panics if p is nil or name is already registered, mirroring the
+// database/sql driver registry
+// @param name:	the name callers will set SessionOptions.Provider to
+// @param p:	the provider instance to serve requests for that name
+func Register(name string, p Provider) {
+	if p == nil {
+		panic("simpleSessions: Register provider is nil")
+	}
+	if _, dup := providers[name]; dup {
+		panic(fmt.Sprintf("simpleSessions: Register called twice for provider %q", name))
+	}
+	providers[name] = p
+}
+
+// === Get Provider ===
+// Look up a provider registered under name
+func getProvider(name string) (Provider, error) {
+	p, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("simpleSessions: unknown provider %q (forgotten Register?)", name)
+	}
+	return p, nil
+}
@@ -0,0 +1,128 @@
+package simpleSessions
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// === File Provider ===
+// Persists sessions as gob-encoded files on disk, one file per session, so
+// they survive process restarts
+// Not registered by default since it needs a directory to write to;
+// construct with NewFileProvider and Register it under whatever name you
+// configure in SessionOptions.Provider
+type FileProvider struct {
+	mu  sync.RWMutex
+	dir string
+}
+
+// === New File Provider ===
+// dir is created if it does not already exist
+// @param dir:	the directory session files are written to
+func NewFileProvider(dir string) (*FileProvider, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileProvider{dir: dir}, nil
+}
+
+func (p *FileProvider) path(id string) string {
+	return filepath.Join(p.dir, id+".sess")
+}
+
+// === Read ===
+func (p *FileProvider) Read(id string) (*Session, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	b, err := os.ReadFile(p.path(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("No session found")
+		}
+		return nil, err
+	}
+	var s Session
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// === Write ===
+func (p *FileProvider) Write(s *Session) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		return err
+	}
+	return os.WriteFile(p.path(s.Id), buf.Bytes(), 0600)
+}
+
+// === Destroy ===
+func (p *FileProvider) Destroy(id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	err := os.Remove(p.path(id))
+	if err != nil && os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// === GC ===
+// Removes all expired sessions and returns the number removed
+func (p *FileProvider) GC() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return 0
+	}
+	var count int
+	now := time.Now()
+	for _, e := range entries {
+		full := filepath.Join(p.dir, e.Name())
+		b, err := os.ReadFile(full)
+		if err != nil {
+			continue
+		}
+		var s Session
+		if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&s); err != nil {
+			continue
+		}
+		if s.Expires.Before(now) {
+			os.Remove(full)
+			count++
+		}
+	}
+	return count
+}
+
+// === All ===
+func (p *FileProvider) All() ([]*Session, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	entries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*Session, 0, len(entries))
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join(p.dir, e.Name()))
+		if err != nil {
+			continue
+		}
+		var s Session
+		if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&s); err != nil {
+			continue
+		}
+		out = append(out, &s)
+	}
+	return out, nil
+}
@@ -0,0 +1,76 @@
+package simpleSessions
+
+import (
+	"testing"
+	"time"
+)
+
+// FileProvider writes one gob-encoded file per session; round-tripping a
+// session through Write/Read/All/Destroy/GC needs no external service, only
+// a scratch directory.
+func TestFileProviderRoundTrip(t *testing.T) {
+	p, err := NewFileProvider(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileProvider: %v", err)
+	}
+
+	s := &Session{
+		Id:      "file-test-id",
+		Expires: time.Now().Add(time.Minute),
+		Uid:     1,
+		Data:    map[interface{}]interface{}{"foo": "bar"},
+	}
+	if err := p.Write(s); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	got, err := p.Read(s.Id)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got.Id != s.Id || got.Uid != s.Uid || got.Data["foo"] != "bar" {
+		t.Fatalf("Read returned %+v, want a round trip of %+v", got, s)
+	}
+
+	all, err := p.All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	if len(all) != 1 || all[0].Id != s.Id {
+		t.Fatalf("All() = %+v, want a single session %q", all, s.Id)
+	}
+
+	if err := p.Destroy(s.Id); err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	if _, err := p.Read(s.Id); err == nil {
+		t.Fatal("Read should fail after Destroy")
+	}
+}
+
+// GC must remove only the files whose Session.Expires has passed.
+func TestFileProviderGCRemovesOnlyExpired(t *testing.T) {
+	p, err := NewFileProvider(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileProvider: %v", err)
+	}
+
+	expired := &Session{Id: "expired", Expires: time.Now().Add(-time.Minute)}
+	live := &Session{Id: "live", Expires: time.Now().Add(time.Minute)}
+	if err := p.Write(expired); err != nil {
+		t.Fatalf("Write expired: %v", err)
+	}
+	if err := p.Write(live); err != nil {
+		t.Fatalf("Write live: %v", err)
+	}
+
+	if got := p.GC(); got != 1 {
+		t.Fatalf("GC() = %d, want 1", got)
+	}
+	if _, err := p.Read(expired.Id); err == nil {
+		t.Fatal("expired session should have been removed by GC")
+	}
+	if _, err := p.Read(live.Id); err != nil {
+		t.Fatalf("live session should have survived GC: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package simpleSessions
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// === Memory Provider ===
+// Default map-backed Provider, giving O(1) lookups by session id
+// Sessions live only in process memory and are lost on restart; registered
+// automatically under the name "memory"
+type MemoryProvider struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+// === New Memory Provider ===
+// Constructor for the memory provider
+func NewMemoryProvider() *MemoryProvider {
+	return &MemoryProvider{sessions: make(map[string]*Session)}
+}
+
+func init() {
+	Register("memory", NewMemoryProvider())
+}
+
+// cloneSession deep-copies a Session so callers never share the Data map or
+// Cookie pointer with what's stored in p.sessions (or with each other) -
+// without this, two concurrent readers of the same session id would write
+// to the same map via Session.Set and crash with "concurrent map writes"
+func cloneSession(s *Session) *Session {
+	cp := *s
+	if s.Data != nil {
+		cp.Data = make(map[interface{}]interface{}, len(s.Data))
+		for k, v := range s.Data {
+			cp.Data[k] = v
+		}
+	}
+	if s.Cookie != nil {
+		c := *s.Cookie
+		cp.Cookie = &c
+	}
+	return &cp
+}
+
+// === Read ===
+func (p *MemoryProvider) Read(id string) (*Session, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	s, ok := p.sessions[id]
+	if !ok {
+		return nil, errors.New("No session found")
+	}
+	return cloneSession(s), nil
+}
+
+// === Write ===
+func (p *MemoryProvider) Write(s *Session) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sessions[s.Id] = cloneSession(s)
+	return nil
+}
+
+// === Destroy ===
+func (p *MemoryProvider) Destroy(id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.sessions, id)
+	return nil
+}
+
+// === GC ===
+// Removes all expired sessions and returns the number removed
+func (p *MemoryProvider) GC() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var count int
+	now := time.Now()
+	for id, s := range p.sessions {
+		if s.Expires.Before(now) {
+			delete(p.sessions, id)
+			count++
+		}
+	}
+	return count
+}
+
+// === All ===
+func (p *MemoryProvider) All() ([]*Session, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	out := make([]*Session, 0, len(p.sessions))
+	for _, s := range p.sessions {
+		out = append(out, cloneSession(s))
+	}
+	return out, nil
+}
@@ -0,0 +1,109 @@
+package simpleSessions
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// === Redis Provider ===
+// Persists sessions in Redis so they can be shared between processes behind
+// a load balancer
+// Not registered by default; construct with NewRedisProvider and Register it
+// under whatever name you configure in SessionOptions.Provider
+type RedisProvider struct {
+	client *redis.Client
+	prefix string
+}
+
+// === New Redis Provider ===
+// opts is passed straight through to redis.NewClient; prefix namespaces keys
+// so the session store can share a Redis instance with other data
+// @param opts:		connection options for the Redis client
+// @param prefix:	key prefix applied to every session written
+func NewRedisProvider(opts *redis.Options, prefix string) *RedisProvider {
+	return &RedisProvider{client: redis.NewClient(opts), prefix: prefix}
+}
+
+func (p *RedisProvider) key(id string) string {
+	return p.prefix + id
+}
+
+// === Read ===
+func (p *RedisProvider) Read(id string) (*Session, error) {
+	ctx := context.Background()
+	b, err := p.client.Get(ctx, p.key(id)).Bytes()
+	if err == redis.Nil {
+		return nil, errors.New("No session found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// === Write ===
+// Sets a TTL matching the session's Expires so Redis reclaims it on its own
+func (p *RedisProvider) Write(s *Session) error {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		return err
+	}
+	ttl := time.Until(s.Expires)
+	if ttl <= 0 {
+		ttl = time.Second
+	}
+	return p.client.Set(ctx, p.key(s.Id), buf.Bytes(), ttl).Err()
+}
+
+// === Destroy ===
+func (p *RedisProvider) Destroy(id string) error {
+	ctx := context.Background()
+	return p.client.Del(ctx, p.key(id)).Err()
+}
+
+// === GC ===
+// Always returns 0: Redis expires keys itself via the TTL set in Write
+func (p *RedisProvider) GC() int {
+	return 0
+}
+
+// === All ===
+// Walks keys with SCAN rather than KEYS so it doesn't block the single
+// threaded Redis server with a full O(n) scan
+func (p *RedisProvider) All() ([]*Session, error) {
+	ctx := context.Background()
+	var out []*Session
+	var cursor uint64
+	for {
+		keys, next, err := p.client.Scan(ctx, cursor, p.prefix+"*", 100).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range keys {
+			b, err := p.client.Get(ctx, k).Bytes()
+			if err != nil {
+				continue
+			}
+			var s Session
+			if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&s); err != nil {
+				continue
+			}
+			out = append(out, &s)
+		}
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+	return out, nil
+}
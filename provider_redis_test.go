@@ -0,0 +1,61 @@
+package simpleSessions
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Requires a real Redis instance; set REDIS_ADDR (e.g. "localhost:6379") to
+// run it, otherwise it's skipped.
+func TestRedisProviderRoundTrip(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set, skipping RedisProvider test")
+	}
+
+	p := NewRedisProvider(&redis.Options{Addr: addr}, "simplesessions-test:")
+
+	s := &Session{
+		Id:      "redis-test-id",
+		Expires: time.Now().Add(time.Minute),
+		Uid:     1,
+		Data:    map[interface{}]interface{}{"foo": "bar"},
+	}
+	defer p.Destroy(s.Id)
+
+	if err := p.Write(s); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	got, err := p.Read(s.Id)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if got.Id != s.Id || got.Uid != s.Uid || got.Data["foo"] != "bar" {
+		t.Fatalf("Read returned %+v, want a round trip of %+v", got, s)
+	}
+
+	all, err := p.All()
+	if err != nil {
+		t.Fatalf("All: %v", err)
+	}
+	var found bool
+	for _, out := range all {
+		if out.Id == s.Id {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("All() = %+v, want it to include %q", all, s.Id)
+	}
+
+	if err := p.Destroy(s.Id); err != nil {
+		t.Fatalf("Destroy: %v", err)
+	}
+	if _, err := p.Read(s.Id); err == nil {
+		t.Fatal("Read should fail after Destroy")
+	}
+}
@@ -0,0 +1,36 @@
+package simpleSessions
+
+import "testing"
+
+func TestSignedCookieVerification(t *testing.T) {
+	sm, err := NewSessionManager(SessionOptions{
+		CookieName:  "sid",
+		Salt:        "test-salt",
+		Timeout:     5,
+		Provider:    "memory",
+		SignCookies: true,
+	})
+	if err != nil {
+		t.Fatalf("NewSessionManager: %v", err)
+	}
+	defer sm.Close()
+
+	id, err := sm.NewSession(1)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	signed := id + "|" + SignSID("test-salt", id)
+
+	if _, err := sm.GetSession(signed); err != nil {
+		t.Fatalf("GetSession with a valid signature: %v", err)
+	}
+
+	if _, err := sm.GetSession(id); err == nil {
+		t.Fatal("GetSession should reject an unsigned cookie value when SignCookies is set")
+	}
+
+	tampered := id + "|" + SignSID("wrong-salt", id)
+	if _, err := sm.GetSession(tampered); err == nil {
+		t.Fatal("GetSession should reject a cookie with a tampered signature")
+	}
+}
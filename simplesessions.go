@@ -12,9 +12,13 @@ import (
 	"time"
 	"errors"
 	"net/http"
+	"sync"
+	"strings"
 	"crypto/hmac"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 )
 
 // === Session Options ===
@@ -24,34 +28,103 @@ import (
 // @param CookieName: 	The name of the cookie
 // @param Salt:			key to feed into the hash algorithm
 // @param Timeout:		multiplies a 1 minuet timeout duration
+// @param Provider:		name of the registered Provider to store sessions in, defaults to "memory"
+// @param GCLifetime:	how often to run the background GC sweep; GC only runs on a timer when this is > 0
+// @param IDLength:		number of crypto/rand bytes read to build a session id, defaults to 32
+// @param SignCookies:	when true, cookie values are `id|hmac(salt,id)` and the mac is checked before the id is trusted
+// @param CookiePath:	Path applied to every cookie the manager writes, defaults to "/"
+// @param Domain:		Domain applied to every cookie the manager writes
+// @param Secure:		Secure flag applied to every cookie the manager writes
+// @param HttpOnly:		HttpOnly flag applied to every cookie the manager writes
+// @param SameSite:		SameSite mode applied to every cookie the manager writes
+// @param MaxAge:		MaxAge applied to every cookie the manager writes
 type SessionOptions struct {
 	CookieName	string
 	Salt		string
 	Timeout		time.Duration
+	Provider	string
+	GCLifetime	time.Duration
+	IDLength	int
+	SignCookies	bool
+	CookiePath	string
+	Domain		string
+	Secure		bool
+	HttpOnly	bool
+	SameSite	http.SameSite
+	MaxAge		int
 }
 
 // === Session Manager ===
-// Composed of our session options and an array of sessions
+// Composed of our session options and a Provider sessions are read from and written to
+// ticker/done/closeOnce drive the background GC goroutine; the Provider is
+// responsible for its own locking around concurrent reads/writes
 type SessionManager struct {
 	Options		SessionOptions
-	Sessions 	[]Session
+	store		Provider
+	ticker		*time.Ticker
+	done		chan struct{}
+	closeOnce	sync.Once
 }
 
 // Session
+// Data holds arbitrary user data set with Set/Get/Delete; complex types must
+// be registered with gob.Register before they can round trip through the
+// file/Redis providers
 type Session struct {
 	Id 		string
 	Expires	time.Time
 	Uid		int
 	Cookie 	*http.Cookie
+	Data	map[interface{}]interface{}
+	sm		*SessionManager
 }
 
 // === New Session Manager ===
 // Constructor for the Session Manager
-// @param siteName:	used to identify the session
-func NewSessionManager(opts SessionOptions) *SessionManager {
-	sm := SessionManager{}
-	sm.Options = opts
-	return &sm
+// Looks up opts.Provider in the registry ("memory" if left blank) and
+// returns an error if it hasn't been registered
+// @param opts:	the options to configure the manager with
+func NewSessionManager(opts SessionOptions) (*SessionManager, error) {
+	name := opts.Provider
+	if name == "" {
+		name = "memory"
+	}
+	store, err := getProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	sm := SessionManager{Options: opts, store: store}
+	if opts.GCLifetime > 0 {
+		sm.ticker = time.NewTicker(opts.GCLifetime)
+		sm.done = make(chan struct{})
+		go sm.gcLoop()
+	}
+	return &sm, nil
+}
+
+// gcLoop runs sm.GC() every tick until Close stops it
+func (sm *SessionManager) gcLoop() {
+	for {
+		select {
+		case <-sm.ticker.C:
+			sm.GC()
+		case <-sm.done:
+			return
+		}
+	}
+}
+
+// === Close ===
+// Stop the background GC goroutine started by NewSessionManager when
+// SessionOptions.GCLifetime is set; a no-op otherwise. Safe to call more than once
+func (sm *SessionManager) Close() {
+	if sm.ticker == nil {
+		return
+	}
+	sm.closeOnce.Do(func() {
+		sm.ticker.Stop()
+		close(sm.done)
+	})
 }
 
 /* ===== Public Methods ==== */
@@ -59,18 +132,41 @@ func NewSessionManager(opts SessionOptions) *SessionManager {
 // === Get Session ===
 // Return a session matching an id and nil error when found
 // Returns a nil pointer value and an error when a session is not found
-// Removes expired sessions
+// Destroys the session if it has expired
 // @param r:	an id to check
 func (sm *SessionManager) GetSession(id string) (*Session, error) {
-	var err error
-	for _, s := range sm.Sessions {
-		if s.Id == id && s.Expires.After(time.Now()) {
-			return &s, err
+	if sm.Options.SignCookies {
+		verified, ok := sm.verifySignedSID(id)
+		if !ok {
+			return nil, errors.New("No session found")
 		}
-		sm.ClearExpired()
+		id = verified
+	}
+	s, err := sm.store.Read(id)
+	if err != nil {
+		return nil, errors.New("No session found")
+	}
+	if s.Expires.Before(time.Now()) {
+		sm.store.Destroy(id)
+		return nil, errors.New("No session found")
+	}
+	s.sm = sm
+	return s, nil
+}
+
+// verifySignedSID splits a signed cookie value of the form id|mac and
+// checks the mac in constant time, so an attacker can't guess ids or tamper
+// with cookies
+func (sm *SessionManager) verifySignedSID(value string) (string, bool) {
+	parts := strings.SplitN(value, "|", 2)
+	if len(parts) != 2 {
+		return "", false
 	}
-	err = errors.New("No session found")
-	return nil, err
+	id, mac := parts[0], parts[1]
+	if !hmac.Equal([]byte(mac), []byte(SignSID(sm.Options.Salt, id))) {
+		return "", false
+	}
+	return id, true
 }
 
 // === Get User Session ===
@@ -78,70 +174,71 @@ func (sm *SessionManager) GetSession(id string) (*Session, error) {
 // Returns a non nil error on fail
 // @param uid:	the id of the user we want to get a session for
 func (sm *SessionManager) GetUserSession(uid int) (string, error) {
-	var err error
-	var sid string
-	var found bool
-	for _, s := range sm.Sessions {
+	all, err := sm.store.All()
+	if err != nil {
+		return "", err
+	}
+	for _, s := range all {
 		if s.Uid == uid {
-			sid = s.Id
-			found = true
+			return s.Id, nil
 		}
 	}
-	if !found {
-		err = errors.New("No session found for a user with this id")
-	}
-	return sid, err
+	return "", errors.New("No session found for a user with this id")
 }
 
 // === Make Session ===
-// Make a new session and return an error, nil error is success case
+// Make a new session and return its id
 // Session Uid -1 indicates a guest session
-// Returns the id of the new session
+// Returns a non-nil error if the provider failed to persist the session
 // @param uid:	the uid of the user to create the session for,
-func (sm *SessionManager) NewSession(uid int) string {
+func (sm *SessionManager) NewSession(uid int) (string, error) {
+	s, err := sm.CreateSession(uid)
+	if err != nil {
+		return "", err
+	}
+	return s.Id, nil
+}
+
+// === Create Session ===
+// Like NewSession, but hands back the live *Session instead of just its id -
+// used by Start and by external packages (like the auth subpackage) that
+// need the session object itself to set its cookie
+// Returns a non-nil error if the provider failed to persist the session
+// @param uid:	the uid of the user to create the session for
+func (sm *SessionManager) CreateSession(uid int) (*Session, error) {
 	s := Session{}
-	s.Id = MakeSID(sm.Options.Salt)
+	s.Id = MakeSID(sm.Options.IDLength)
 	s.Expires = time.Now().Add(sm.Options.Timeout * time.Minute)
 	s.Uid = uid
-	sm.Sessions = append(sm.Sessions, s)
-	return s.Id
+	s.Data = make(map[interface{}]interface{})
+	s.sm = sm
+	s.Cookie = sm.newCookie(sm.cookieValue(&s), s.Expires)
+	if err := sm.store.Write(&s); err != nil {
+		return nil, err
+	}
+	return &s, nil
 }
 
 // === Delete Session ===
 // Remove a session matching an id
 // @param id:	the id for the sesison to delete
 func (sm *SessionManager) DeleteSession(sid string) {
-	for i, s := range sm.Sessions {
-		if s.Id == sid {
-			sm.Sessions = append(sm.Sessions[:i], sm.Sessions[i+1:]...)
-		}
-	}
+	sm.store.Destroy(sid)
 }
 
-// === Set Cookie ===
-// Push a session cookie to the response writer
-// @param w: 	the writer interface
-// @param id: 	the session id to set a cookie for
-func (sm *SessionManager) SetCookie(w http.ResponseWriter, s *Session) {
-	c := s.Cookie
-	c.Name = sm.Options.CookieName
-	c.Value = s.Id
-	c.Expires = s.Expires
-	http.SetCookie(w, c)
+// === GC ===
+// Clear expired sessions from the provider
+// Called automatically on a timer when SessionOptions.GCLifetime is set, but
+// safe to call manually too
+// Returns an int value of the number of sessions cleared
+func (sm *SessionManager) GC() int {
+	return sm.store.GC()
 }
 
 // === Clear Expired Session ===
-// Go through the session table and clear out all expired sessions
-// Returns an int value of the number of sessions cleared
+// Deprecated alias for GC, kept for existing callers
 func (sm *SessionManager) ClearExpired() int {
-	var count int
-	for i, s := range sm.Sessions {
-		if s.Expires.Before(time.Now()) {
-			sm.Sessions = append(sm.Sessions[:i], sm.Sessions[i+1:]...)
-			count++
-		}
-	}
-	return count
+	return sm.GC()
 }
 
 // === Set Uid ===
@@ -151,28 +248,42 @@ func (sm *SessionManager) ClearExpired() int {
 // @param session: 	the session we want to change the uid of
 // @param uid:	the uid to set on the session
 func (sm *SessionManager) SetUID(session *Session, uid int) (string, error) {
-	var err error
-	var sid string
-	for _, s := range sm.Sessions {
-		if s.Uid == uid {
-			err = errors.New("Session already exists")
-			sid = s.Id
-		}
+	if sid, err := sm.GetUserSession(uid); err == nil {
+		return sid, errors.New("Session already exists")
 	}
-	if err == nil {
-		session.Uid = uid
+	session.Uid = uid
+	if err := sm.store.Write(session); err != nil {
+		return "", err
 	}
-	return sid, err
+	return "", nil
 }
 
 // === Make Session ID ===
-// Return a string for the new session id
-// @param salt: value written to byte slice with time.now to randomize output
-func MakeSID(salt string) string {
-	key := []byte(salt + time.Now().String())
-	h := hmac.New(sha256.New, key)
-	h.Write([]byte(key))
-	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+// Generate a cryptographically random session id
+// Reads idLength bytes from crypto/rand and base64url-encodes them
+// @param idLength: number of random bytes to read, <= 0 uses the default of 32
+func MakeSID(idLength int) string {
+	if idLength <= 0 {
+		idLength = 32
+	}
+	b := make([]byte, idLength)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand failing means the OS CSPRNG is unavailable; there is
+		// no safe fallback for something that has to be unguessable
+		panic("simpleSessions: crypto/rand unavailable: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// === Sign Session ID ===
+// Return a hex-encoded HMAC-SHA256 of id keyed with salt
+// Used to build a tamper-evident cookie value of the form id|SignSID(salt,id)
+// @param salt:	the key to HMAC with, shared with verifySignedSID
+// @param id:	the session id to sign
+func SignSID(salt, id string) string {
+	h := hmac.New(sha256.New, []byte(salt))
+	h.Write([]byte(id))
+	return hex.EncodeToString(h.Sum(nil))
 }
 
 